@@ -0,0 +1,22 @@
+package photoprism
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli"
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "PhotoPrism"
+	app.Usage = "Browse, organize and share your photo collection"
+	app.Commands = []cli.Command{
+		MigrateCommand,
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
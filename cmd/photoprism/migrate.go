@@ -0,0 +1,94 @@
+package photoprism
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+// MigrateCommand configures the command name and its up, down and status subcommands.
+var MigrateCommand = cli.Command{
+	Name:  "migrate",
+	Usage: "Applies versioned schema migrations",
+	Subcommands: []cli.Command{
+		{
+			Name:   "up",
+			Usage:  "Applies all pending migrations",
+			Action: migrateUpAction,
+		},
+		{
+			Name:      "down",
+			Usage:     "Reverts the last N applied migrations",
+			ArgsUsage: "[N]",
+			Action:    migrateDownAction,
+		},
+		{
+			Name:   "status",
+			Usage:  "Lists all registered migrations and whether they were applied",
+			Action: migrateStatusAction,
+		},
+	},
+}
+
+func migrateUpAction(ctx *cli.Context) error {
+	conf := config.NewConfig(ctx)
+
+	if err := conf.InitDb(); err != nil {
+		return err
+	}
+
+	return entity.MigrateUp(context.Background())
+}
+
+func migrateDownAction(ctx *cli.Context) error {
+	n := 1
+
+	if ctx.Args().Present() {
+		parsed, err := strconv.Atoi(ctx.Args().First())
+
+		if err != nil {
+			return fmt.Errorf("migrate: invalid step count %q", ctx.Args().First())
+		}
+
+		n = parsed
+	}
+
+	conf := config.NewConfig(ctx)
+
+	if err := conf.InitDb(); err != nil {
+		return err
+	}
+
+	return entity.MigrateDown(context.Background(), n)
+}
+
+func migrateStatusAction(ctx *cli.Context) error {
+	conf := config.NewConfig(ctx)
+
+	if err := conf.InitDb(); err != nil {
+		return err
+	}
+
+	status, err := entity.MigrateStatus()
+
+	if err != nil {
+		return err
+	}
+
+	for _, s := range status {
+		applied := "pending"
+
+		if s.Applied {
+			applied = "applied"
+		}
+
+		fmt.Printf("%s  %-40s %s\n", s.Version, s.Name, applied)
+	}
+
+	return nil
+}
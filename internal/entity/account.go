@@ -0,0 +1,5 @@
+package entity
+
+func init() {
+	MustRegister("accounts", &Account{})
+}
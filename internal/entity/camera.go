@@ -0,0 +1,8 @@
+package entity
+
+func init() {
+	MustRegister("cameras", &Camera{}, WithSeed(func() error {
+		CreateUnknownCamera()
+		return nil
+	}))
+}
@@ -0,0 +1,5 @@
+package entity
+
+func init() {
+	MustRegister("categories", &Category{}, DependsOn("labels"))
+}
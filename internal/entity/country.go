@@ -0,0 +1,8 @@
+package entity
+
+func init() {
+	MustRegister("countries", &Country{}, WithSeed(func() error {
+		CreateUnknownCountry()
+		return nil
+	}))
+}
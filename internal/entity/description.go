@@ -0,0 +1,5 @@
+package entity
+
+func init() {
+	MustRegister("descriptions", &Description{}, DependsOn("photos"))
+}
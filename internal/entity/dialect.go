@@ -0,0 +1,115 @@
+package entity
+
+import "fmt"
+
+// Supported Gorm driver names, as passed in Gorm.Driver.
+const (
+	MySQL    = "mysql"
+	Postgres = "postgres"
+	SQLite   = "sqlite3"
+)
+
+// Dialect abstracts the driver-specific SQL needed to manage the schema,
+// so that entity does not have to special-case MySQL everywhere.
+type Dialect interface {
+	// Name returns the Gorm driver name this dialect was built for.
+	Name() string
+
+	// TableExists reports whether a table with the given name already exists.
+	TableExists(name string) (bool, error)
+
+	// MigrationHints returns driver-specific notes shown to the user when
+	// AutoMigrate cannot perform an operation (e.g. renaming a column).
+	MigrationHints() []string
+}
+
+// DbDialect returns the driver name of the current database connection, as
+// recorded on the active Gorm provider, so it always reflects whatever
+// driver the connection was actually opened with instead of a separately
+// tracked value that could drift out of sync.
+func DbDialect() string {
+	if !HasDbProvider() {
+		return MySQL
+	}
+
+	return DbProvider().Driver
+}
+
+// NewDialect returns the Dialect implementation for the given Gorm driver
+// name, or an error if the driver is not supported.
+func NewDialect(driver string) (Dialect, error) {
+	switch driver {
+	case MySQL, "mariadb":
+		return mysqlDialect{}, nil
+	case Postgres:
+		return postgresDialect{}, nil
+	case SQLite, "sqlite":
+		return sqliteDialect{}, nil
+	default:
+		return nil, fmt.Errorf("entity: unsupported database driver %q", driver)
+	}
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string { return MySQL }
+
+func (mysqlDialect) TableExists(name string) (bool, error) {
+	err := Db().Raw(fmt.Sprintf("DESCRIBE `%s`", name)).Scan(&struct{}{}).Error
+
+	if err == nil {
+		return true, nil
+	}
+
+	return false, err
+}
+
+func (mysqlDialect) MigrationHints() []string {
+	return []string{"MySQL/MariaDB supports online column renames via ALTER TABLE ... CHANGE"}
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string { return Postgres }
+
+func (postgresDialect) TableExists(name string) (bool, error) {
+	var count int
+
+	err := Db().Raw(
+		"SELECT count(*) FROM information_schema.tables WHERE table_name = ? AND table_schema = current_schema()",
+		name,
+	).Row().Scan(&count)
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (postgresDialect) MigrationHints() []string {
+	return []string{"Postgres requires USING clauses when changing a column's type"}
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string { return SQLite }
+
+func (sqliteDialect) TableExists(name string) (bool, error) {
+	var count int
+
+	err := Db().Raw(
+		"SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = ?",
+		name,
+	).Row().Scan(&count)
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (sqliteDialect) MigrationHints() []string {
+	return []string{"SQLite cannot drop columns or change column types without rebuilding the table"}
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDialect_SelectsDriver(t *testing.T) {
+	cases := []struct {
+		driver  string
+		name    string
+		wantErr bool
+	}{
+		{MySQL, MySQL, false},
+		{"mariadb", MySQL, false},
+		{Postgres, Postgres, false},
+		{SQLite, SQLite, false},
+		{"sqlite", SQLite, false},
+		{"mssql", "", true},
+	}
+
+	for _, c := range cases {
+		dialect, err := NewDialect(c.driver)
+
+		if c.wantErr {
+			assert.Error(t, err)
+			assert.Nil(t, dialect)
+			continue
+		}
+
+		assert.NoError(t, err)
+
+		if assert.NotNil(t, dialect) {
+			assert.Equal(t, c.name, dialect.Name())
+		}
+	}
+}
+
+func TestDbDialect_DefaultsToMySQL(t *testing.T) {
+	if HasDbProvider() {
+		t.Skip("a db provider is already registered, default-to-MySQL path is not reachable")
+	}
+
+	assert.Equal(t, MySQL, DbDialect())
+}
@@ -10,6 +10,7 @@ https://github.com/photoprism/photoprism/wiki/Storage
 package entity
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -29,110 +30,214 @@ func logError(result *gorm.DB) {
 
 type Types map[string]interface{}
 
-// List of database entities and their table names.
-var Entities = Types{
-	"accounts":        &Account{},
-	"files":           &File{},
-	"files_share":     &FileShare{},
-	"files_sync":      &FileSync{},
-	"photos":          &Photo{},
-	"descriptions":    &Description{},
-	"places":          &Place{},
-	"locations":       &Location{},
-	"cameras":         &Camera{},
-	"lenses":          &Lens{},
-	"countries":       &Country{},
-	"albums":          &Album{},
-	"photos_albums":   &PhotoAlbum{},
-	"labels":          &Label{},
-	"categories":      &Category{},
-	"photos_labels":   &PhotoLabel{},
-	"keywords":        &Keyword{},
-	"photos_keywords": &PhotoKeyword{},
-	"links":           &Link{},
+// Entities returns every registered database entity, keyed by table name.
+// Entities self-register from their own files via Register/MustRegister in
+// init(), see register.go, so plugins and optional subsystems can
+// contribute tables without editing this file.
+func Entities() Types {
+	return RegisteredEntities()
 }
 
-// WaitForMigration waits for the database migration to be successful.
-func (list Types) WaitForMigration() {
+// WaitForMigration waits for the database migration to be successful, or
+// until ctx is cancelled. It reports {table, attempt, elapsed} progress
+// events as event.Log so long-running migrations (e.g. on first startup)
+// can be surfaced to the caller instead of appearing to hang.
+func (list Types) WaitForMigration(ctx context.Context) error {
+	dialect, err := NewDialect(DbDialect())
+
+	if err != nil {
+		return err
+	}
+
 	attempts := 100
+	started := time.Now()
 
-	for name := range list {
+	for _, name := range list.orderedNames() {
 		for i := 0; i <= attempts; i++ {
-			if err := Db().Raw(fmt.Sprintf("DESCRIBE `%s`", name)).Scan(&struct{}{}).Error; err == nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			if exists, err := dialect.TableExists(name); exists && err == nil {
 				log.Debugf("entity: table %s migrated", name)
 				break
-			} else {
+			} else if err != nil {
 				log.Debugf("entity: %s", err.Error())
 			}
 
+			log.Infof("entity: waiting for table %s (attempt %d, %s elapsed)", name, i, time.Since(started))
+
 			if i == attempts {
-				panic("migration failed")
+				return fmt.Errorf("entity: migration of table %s failed after %d attempts", name, attempts)
 			}
 
-			time.Sleep(50 * time.Millisecond)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(50 * time.Millisecond):
+			}
 		}
 	}
+
+	log.Infof("entity: migration completed in %s", time.Since(started))
+
+	return nil
 }
 
-// Drop migrates all database tables of registered entities.
-func (list Types) Migrate() {
-	for _, entity := range list {
-		if err := Db().AutoMigrate(entity).Error; err != nil {
-			panic(err)
+// Migrate migrates all database tables of registered entities, in an order
+// that respects the dependencies declared via DependsOn. If AutoMigrate
+// fails, the error is annotated with the active dialect's MigrationHints,
+// since AutoMigrate cannot perform some changes (e.g. renaming a column)
+// and the hint explains what to do about it instead of just that it failed.
+func (list Types) Migrate() error {
+	for _, name := range list.orderedNames() {
+		if err := Db().AutoMigrate(list[name]).Error; err != nil {
+			return fmt.Errorf("entity: failed migrating %s (%s)%s", name, err, migrationHintSuffix())
 		}
 	}
+
+	return nil
 }
 
-// Drop drops all database tables of registered entities.
-func (list Types) Drop() {
-	for _, entity := range list {
-		if err := Db().DropTableIfExists(entity).Error; err != nil {
-			panic(err)
+// migrationHintSuffix returns the active dialect's MigrationHints formatted
+// for appending to an AutoMigrate error, or an empty string if the dialect
+// can't be determined or has no hints.
+func migrationHintSuffix() string {
+	dialect, err := NewDialect(DbDialect())
+
+	if err != nil {
+		return ""
+	}
+
+	hints := dialect.MigrationHints()
+
+	if len(hints) == 0 {
+		return ""
+	}
+
+	suffix := ""
+
+	for _, hint := range hints {
+		suffix += fmt.Sprintf("\n  hint: %s", hint)
+	}
+
+	return suffix
+}
+
+// Drop drops all database tables of registered entities, in reverse
+// dependency order so that referencing tables are dropped before the
+// tables they depend on.
+func (list Types) Drop() error {
+	names := list.orderedNames()
+
+	for i := len(names) - 1; i >= 0; i-- {
+		if err := Db().DropTableIfExists(list[names[i]]).Error; err != nil {
+			return fmt.Errorf("entity: failed dropping %s (%s)", names[i], err)
 		}
 	}
+
+	return nil
+}
+
+// orderedNames returns the names in list, ordered by their position in the
+// registration graph so that migration and drop order is deterministic and
+// dependency-respecting instead of Go's randomized map iteration.
+func (list Types) orderedNames() []string {
+	result := make([]string, 0, len(list))
+
+	for _, name := range registrationOrder() {
+		if _, ok := list[name]; ok {
+			result = append(result, name)
+		}
+	}
+
+	return result
 }
 
-// MigrateDb creates all tables and inserts default entities as needed.
-func MigrateDb() {
-	Entities.Migrate()
-	Entities.WaitForMigration()
+// MigrateDb creates all tables and inserts default entities as needed. It
+// first runs AutoMigrate for new tables and columns, then applies any
+// pending versioned migrations registered via RegisterMigration. It honors
+// ctx cancellation so orchestrators (Kubernetes init containers, systemd)
+// can abort the migration gracefully instead of it panicking mid-startup.
+func MigrateDb(ctx context.Context) error {
+	if err := Entities().Migrate(); err != nil {
+		return err
+	}
+
+	if err := Entities().WaitForMigration(ctx); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if err := MigrateUp(ctx); err != nil {
+		return err
+	}
 
-	CreateUnknownPlace()
-	CreateUnknownCountry()
-	CreateUnknownCamera()
-	CreateUnknownLens()
+	return RunSeeds(ctx)
 }
 
 // DropTables drops database tables for all known entities.
-func DropTables() {
-	Entities.Drop()
+func DropTables() error {
+	return Entities().Drop()
 }
 
 // ResetDb drops database tables for all known entities and re-creates them with fixtures.
-func ResetDb(testFixtures bool) {
-	DropTables()
-	MigrateDb()
+//
+// No entity currently attaches fixtures via WithFixtures, so LoadFixtures
+// would silently insert nothing. Keep calling CreateTestFixtures until
+// entities have actually migrated their fixture data over to WithFixtures.
+func ResetDb(testFixtures bool) error {
+	if err := DropTables(); err != nil {
+		return err
+	}
+
+	if err := MigrateDb(context.Background()); err != nil {
+		return err
+	}
 
 	if testFixtures {
 		CreateTestFixtures()
 	}
+
+	return nil
 }
 
 // InitTestFixtures resets the database and test fixtures once.
 func InitTestFixtures() {
 	resetFixturesOnce.Do(func() {
-		ResetDb(true)
+		if err := ResetDb(true); err != nil {
+			panic(err)
+		}
 	})
 }
 
 // InitTestDb connects to and completely initializes the test database incl fixtures.
 func InitTestDb(dsn string) *Gorm {
+	return InitTestDbWithDriver(MySQL, dsn)
+}
+
+// InitTestDbWithDriver connects to and completely initializes a test database
+// using the given driver ("mysql", "postgres" or "sqlite3") incl fixtures.
+// Prefer SQLite for tests that do not need to exercise driver-specific SQL,
+// as it needs no external database server.
+func InitTestDbWithDriver(driver, dsn string) *Gorm {
 	if HasDbProvider() {
 		return nil
 	}
 
+	if _, err := NewDialect(driver); err != nil {
+		panic(err)
+	}
+
 	db := &Gorm{
-		Driver: "mysql",
+		Driver: driver,
 		Dsn:    dsn,
 	}
 
@@ -0,0 +1,8 @@
+package entity
+
+func init() {
+	MustRegister("lenses", &Lens{}, WithSeed(func() error {
+		CreateUnknownLens()
+		return nil
+	}))
+}
@@ -0,0 +1,5 @@
+package entity
+
+func init() {
+	MustRegister("locations", &Location{}, DependsOn("places"))
+}
@@ -0,0 +1,215 @@
+package entity
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// schemaMigrationsTable is the name of the table used to track which
+// versioned migrations have already been applied.
+const schemaMigrationsTable = "schema_migrations"
+
+// SchemaMigration represents a single applied migration record.
+type SchemaMigration struct {
+	Version   string `gorm:"primary_key"`
+	AppliedAt time.Time
+}
+
+// TableName returns the name of the schema migrations tracking table.
+func (SchemaMigration) TableName() string {
+	return schemaMigrationsTable
+}
+
+// Migration is a single, numbered schema change with reversible steps. Both
+// Up and Down are required; RegisterMigration rejects a migration that
+// can't be reverted rather than let MigrateDown panic on a nil Down later.
+// Version must sort lexically in application order, so migrations are
+// conventionally named with a timestamp prefix, e.g. "20200101120000".
+type Migration struct {
+	Version string
+	Name    string
+	Up      func(db *gorm.DB) error
+	Down    func(db *gorm.DB) error
+}
+
+// migrations holds every registered migration, keyed by version.
+var migrations = map[string]Migration{}
+
+// RegisterMigration adds a migration to the registry. It is meant to be
+// called from init() in the file that defines the migration, so that
+// migrations live next to the code they affect instead of one long list.
+func RegisterMigration(m Migration) {
+	if _, exists := migrations[m.Version]; exists {
+		panic(fmt.Sprintf("entity: migration %s already registered", m.Version))
+	}
+
+	if m.Up == nil || m.Down == nil {
+		panic(fmt.Sprintf("entity: migration %s must define both Up and Down", m.Version))
+	}
+
+	migrations[m.Version] = m
+}
+
+// orderedMigrations returns all registered migrations sorted by version.
+func orderedMigrations() []Migration {
+	result := make([]Migration, 0, len(migrations))
+
+	for _, m := range migrations {
+		result = append(result, m)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result
+}
+
+// appliedMigrations returns the set of migration versions already recorded
+// in the schema_migrations table, creating the table on first use.
+func appliedMigrations() (map[string]bool, error) {
+	if err := Db().AutoMigrate(&SchemaMigration{}).Error; err != nil {
+		return nil, err
+	}
+
+	var rows []SchemaMigration
+
+	if err := Db().Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]bool, len(rows))
+
+	for _, row := range rows {
+		result[row.Version] = true
+	}
+
+	return result, nil
+}
+
+// MigrateUp applies all pending versioned migrations in order. Each
+// migration runs inside its own transaction where the driver supports it,
+// so a failure only rolls back the migration that caused it. It checks ctx
+// between migrations so a long batch of backfills can still be cancelled
+// instead of running to completion regardless of shutdown signals.
+func MigrateUp(ctx context.Context) error {
+	applied, err := appliedMigrations()
+
+	if err != nil {
+		return err
+	}
+
+	for _, m := range orderedMigrations() {
+		if applied[m.Version] {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		log.Infof("entity: applying migration %s (%s)", m.Version, m.Name)
+
+		tx := Db().Begin()
+
+		if err := m.Up(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("entity: migration %s failed: %s", m.Version, err)
+		}
+
+		if err := tx.Create(&SchemaMigration{Version: m.Version, AppliedAt: time.Now().UTC()}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MigrateDown reverts the last n applied migrations, most recent first. It
+// checks ctx between migrations so it can be cancelled partway through,
+// just like MigrateUp.
+func MigrateDown(ctx context.Context, n int) error {
+	applied, err := appliedMigrations()
+
+	if err != nil {
+		return err
+	}
+
+	all := orderedMigrations()
+
+	for i := len(all) - 1; i >= 0 && n > 0; i-- {
+		m := all[i]
+
+		if !applied[m.Version] {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		log.Infof("entity: reverting migration %s (%s)", m.Version, m.Name)
+
+		tx := Db().Begin()
+
+		if err := m.Down(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("entity: reverting migration %s failed: %s", m.Version, err)
+		}
+
+		if err := tx.Delete(&SchemaMigration{Version: m.Version}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			return err
+		}
+
+		n--
+	}
+
+	return nil
+}
+
+// MigrationStatus reports, for every registered migration, whether it has
+// already been applied. Used by the "migrate status" CLI command.
+type MigrationStatus struct {
+	Version string
+	Name    string
+	Applied bool
+}
+
+// MigrateStatus returns the current status of every registered migration.
+func MigrateStatus() ([]MigrationStatus, error) {
+	applied, err := appliedMigrations()
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]MigrationStatus, 0, len(migrations))
+
+	for _, m := range orderedMigrations() {
+		result = append(result, MigrationStatus{
+			Version: m.Version,
+			Name:    m.Name,
+			Applied: applied[m.Version],
+		})
+	}
+
+	return result, nil
+}
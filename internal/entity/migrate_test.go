@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterMigration_PanicsOnDuplicateVersion(t *testing.T) {
+	saved := migrations
+	migrations = map[string]Migration{}
+	defer func() { migrations = saved }()
+
+	m := Migration{
+		Version: "20200101000000",
+		Name:    "test migration",
+		Up:      func(db *gorm.DB) error { return nil },
+		Down:    func(db *gorm.DB) error { return nil },
+	}
+
+	RegisterMigration(m)
+
+	assert.Panics(t, func() {
+		RegisterMigration(m)
+	})
+}
+
+func TestRegisterMigration_PanicsOnMissingDown(t *testing.T) {
+	saved := migrations
+	migrations = map[string]Migration{}
+	defer func() { migrations = saved }()
+
+	assert.Panics(t, func() {
+		RegisterMigration(Migration{
+			Version: "20200101000000",
+			Name:    "test migration",
+			Up:      func(db *gorm.DB) error { return nil },
+		})
+	})
+}
+
+func TestOrderedMigrations_SortsByVersion(t *testing.T) {
+	saved := migrations
+	migrations = map[string]Migration{}
+	defer func() { migrations = saved }()
+
+	noop := func(db *gorm.DB) error { return nil }
+
+	RegisterMigration(Migration{Version: "20200301000000", Name: "third", Up: noop, Down: noop})
+	RegisterMigration(Migration{Version: "20200101000000", Name: "first", Up: noop, Down: noop})
+	RegisterMigration(Migration{Version: "20200201000000", Name: "second", Up: noop, Down: noop})
+
+	ordered := orderedMigrations()
+
+	assert.Len(t, ordered, 3)
+	assert.Equal(t, "first", ordered[0].Name)
+	assert.Equal(t, "second", ordered[1].Name)
+	assert.Equal(t, "third", ordered[2].Name)
+}
@@ -0,0 +1,5 @@
+package entity
+
+func init() {
+	MustRegister("photos", &Photo{}, DependsOn("cameras", "lenses", "places", "locations"))
+}
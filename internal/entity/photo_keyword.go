@@ -0,0 +1,5 @@
+package entity
+
+func init() {
+	MustRegister("photos_keywords", &PhotoKeyword{}, DependsOn("photos", "keywords"))
+}
@@ -0,0 +1,5 @@
+package entity
+
+func init() {
+	MustRegister("photos_labels", &PhotoLabel{}, DependsOn("photos", "labels"))
+}
@@ -0,0 +1,8 @@
+package entity
+
+func init() {
+	MustRegister("places", &Place{}, DependsOn("countries"), WithSeed(func() error {
+		CreateUnknownPlace()
+		return nil
+	}))
+}
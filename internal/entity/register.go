@@ -0,0 +1,188 @@
+package entity
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// registration describes one entity contributed via Register, including
+// everything needed to migrate, seed and order it relative to others.
+type registration struct {
+	Name      string
+	Model     interface{}
+	DependsOn []string
+	Fixtures  interface{}
+	Seed      func() error
+}
+
+// RegisterOption configures an optional aspect of a Register call.
+type RegisterOption func(*registration)
+
+// WithFixtures attaches test fixtures that LoadFixtures inserts for this
+// entity. CreateTestFixtures does not load them.
+func WithFixtures(fixtures interface{}) RegisterOption {
+	return func(r *registration) {
+		r.Fixtures = fixtures
+	}
+}
+
+// WithSeed attaches a seed function that inserts default rows (e.g. an
+// "unknown" placeholder) once the entity's table has been migrated.
+func WithSeed(seed func() error) RegisterOption {
+	return func(r *registration) {
+		r.Seed = seed
+	}
+}
+
+// DependsOn declares that this entity's table must be migrated after the
+// named entities, e.g. because it has a foreign key referencing them.
+func DependsOn(names ...string) RegisterOption {
+	return func(r *registration) {
+		r.DependsOn = append(r.DependsOn, names...)
+	}
+}
+
+// registry holds every entity contributed via Register, keyed by name.
+var registry = map[string]*registration{}
+
+// Register adds a database entity under the given table name. It is meant
+// to be called from init() in the file that defines the entity's model, so
+// that optional subsystems (faces, videos, external accounts, ...) can
+// contribute tables without editing a central list.
+func Register(name string, model interface{}, opts ...RegisterOption) error {
+	if _, exists := registry[name]; exists {
+		return fmt.Errorf("entity: %s already registered", name)
+	}
+
+	r := &registration{Name: name, Model: model}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	registry[name] = r
+
+	return nil
+}
+
+// MustRegister is like Register but panics if the name is already taken.
+// Use it from init() where a registration failure means a programming
+// error, not a runtime condition.
+func MustRegister(name string, model interface{}, opts ...RegisterOption) {
+	if err := Register(name, model, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// registrationOrder returns registered entity names ordered so that every
+// entity appears after the entities it DependsOn, breaking ties
+// alphabetically for a stable, reproducible migration order.
+func registrationOrder() []string {
+	names := make([]string, 0, len(registry))
+
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var (
+		result  = make([]string, 0, len(names))
+		visited = make(map[string]bool, len(names))
+		visit   func(name string, stack []string)
+	)
+
+	visit = func(name string, stack []string) {
+		if visited[name] {
+			return
+		}
+
+		for _, s := range stack {
+			if s == name {
+				panic(fmt.Sprintf("entity: circular dependency detected: %v", append(stack, name)))
+			}
+		}
+
+		r, ok := registry[name]
+
+		if !ok {
+			return
+		}
+
+		deps := append([]string(nil), r.DependsOn...)
+		sort.Strings(deps)
+
+		for _, dep := range deps {
+			visit(dep, append(stack, name))
+		}
+
+		if !visited[name] {
+			visited[name] = true
+			result = append(result, name)
+		}
+	}
+
+	for _, name := range names {
+		visit(name, nil)
+	}
+
+	return result
+}
+
+// RegisteredEntities returns every registered entity's model, keyed by
+// table name, in the same shape the former hard-coded Entities map used.
+func RegisteredEntities() Types {
+	result := make(Types, len(registry))
+
+	for name, r := range registry {
+		result[name] = r.Model
+	}
+
+	return result
+}
+
+// RunSeeds calls the Seed function attached via WithSeed for every
+// registered entity that has one, in dependency order, so callers no
+// longer need a hard-coded list of CreateUnknownX-style calls. It checks
+// ctx between entities so seeding can be cancelled partway through.
+func RunSeeds(ctx context.Context) error {
+	for _, name := range registrationOrder() {
+		r, ok := registry[name]
+
+		if !ok || r.Seed == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if err := r.Seed(); err != nil {
+			return fmt.Errorf("entity: seeding %s failed (%s)", name, err)
+		}
+	}
+
+	return nil
+}
+
+// LoadFixtures inserts the fixtures attached via WithFixtures for every
+// registered entity that has them, in dependency order. ResetDb does not
+// call it; it loads test data through CreateTestFixtures instead.
+func LoadFixtures() error {
+	for _, name := range registrationOrder() {
+		r, ok := registry[name]
+
+		if !ok || r.Fixtures == nil {
+			continue
+		}
+
+		if err := Db().Create(r.Fixtures).Error; err != nil {
+			return fmt.Errorf("entity: loading fixtures for %s failed (%s)", name, err)
+		}
+	}
+
+	return nil
+}
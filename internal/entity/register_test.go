@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withScratchRegistry runs fn against an empty registry and restores the
+// real one afterwards, so tests that register scratch entities don't
+// poison every other test in this package.
+func withScratchRegistry(fn func()) {
+	saved := registry
+	registry = map[string]*registration{}
+	defer func() { registry = saved }()
+
+	fn()
+}
+
+func TestRegister_DuplicateName(t *testing.T) {
+	withScratchRegistry(func() {
+		assert.NoError(t, Register("test_register_dup", 1))
+		assert.Error(t, Register("test_register_dup", 2))
+	})
+}
+
+func TestMustRegister_PanicsOnDuplicate(t *testing.T) {
+	withScratchRegistry(func() {
+		MustRegister("test_must_register_dup", 1)
+
+		assert.Panics(t, func() {
+			MustRegister("test_must_register_dup", 2)
+		})
+	})
+}
+
+func TestRegistrationOrder_RespectsDependsOn(t *testing.T) {
+	withScratchRegistry(func() {
+		MustRegister("test_order_a", 1)
+		MustRegister("test_order_b", 1, DependsOn("test_order_a"))
+
+		order := registrationOrder()
+
+		posA := indexOf(order, "test_order_a")
+		posB := indexOf(order, "test_order_b")
+
+		assert.True(t, posA >= 0 && posB >= 0)
+		assert.Less(t, posA, posB)
+	})
+}
+
+func TestRegistrationOrder_PanicsOnCycle(t *testing.T) {
+	withScratchRegistry(func() {
+		MustRegister("test_cycle_a", 1, DependsOn("test_cycle_b"))
+		MustRegister("test_cycle_b", 1, DependsOn("test_cycle_a"))
+
+		assert.Panics(t, func() {
+			registrationOrder()
+		})
+	})
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+
+	return -1
+}